@@ -0,0 +1,331 @@
+// Package commenter is the pipeline that ties the rest of this module
+// together: it detects a file's language, decides which declarations'
+// doc comments are due for regeneration, asks a Generator (in
+// production, an LLM call this package deliberately doesn't make itself)
+// for the new doc text, and attaches the result to the right AST node.
+// Only Go is wired up so far; other languages detected by commentstyle
+// are reported as not yet supported.
+package commenter
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/pelikhan/action-continuous-comments/internal/commentsig"
+	"github.com/pelikhan/action-continuous-comments/internal/commentstyle"
+	"github.com/pelikhan/action-continuous-comments/internal/constval"
+	"github.com/pelikhan/action-continuous-comments/internal/docattach"
+)
+
+// Generator supplies the doc comment body for a declaration. A real
+// caller backs this with an LLM call; tests back it with a fake.
+type Generator interface {
+	// Doc returns the doc comment text for the declaration named name.
+	// The text must start with the declaration's bare identifier, per Go
+	// convention: for a method or interface method, whose name is
+	// "<Type>.<Method>", that is the part after the last dot.
+	// AttachFuncDoc and AttachFieldDoc reject it otherwise.
+	Doc(name string) (string, error)
+}
+
+// Options configures a Run.
+type Options struct {
+	// Policy controls which existing comments are eligible for
+	// regeneration; see commentsig.Policy.
+	Policy commentsig.Policy
+	// DryRun, if true, skips generation and attachment entirely: Run
+	// returns the unmodified source together with the plan that would
+	// have been executed.
+	DryRun bool
+	// Generator supplies new doc text. Required unless DryRun is true.
+	Generator Generator
+}
+
+// Run plans and, unless opts.DryRun, applies doc comment regeneration
+// for every function, method, type, const and var declaration in the Go
+// source file named filename. Every generated comment ends with a
+// commentsig marker line recording the declaration's signature. It
+// returns the (possibly unchanged) source alongside the plan describing
+// what was or would be regenerated.
+func Run(filename string, src []byte, opts Options) ([]byte, commentsig.Report, error) {
+	style, ok := commentstyle.Detect(filename, commentstyle.DetectConfig{})
+	if !ok || style.Name != "Go" {
+		name := "unknown"
+		if ok {
+			name = style.Name
+		}
+		return nil, commentsig.Report{}, fmt.Errorf("commenter: %s: language %q is not wired up yet (only Go is)", filename, name)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, commentsig.Report{}, fmt.Errorf("commenter: parsing %s: %w", filename, err)
+	}
+
+	report, err := commentsig.Plan(fset, file, opts.Policy)
+	if err != nil {
+		return nil, report, fmt.Errorf("commenter: planning %s: %w", filename, err)
+	}
+	if opts.DryRun {
+		return src, report, nil
+	}
+
+	out := src
+	for _, d := range report.Decisions {
+		if !d.Regenerate {
+			continue
+		}
+		fset, file, err := reparse(filename, out)
+		if err != nil {
+			return nil, report, err
+		}
+		target := findTarget(file, d.Name)
+		if target.fn == nil && target.gd == nil && target.field == nil {
+			return nil, report, fmt.Errorf("commenter: %s: declaration %q vanished after a previous edit", filename, d.Name)
+		}
+		text, err := opts.Generator.Doc(d.Name)
+		if err != nil {
+			return nil, report, fmt.Errorf("commenter: generating doc for %s: %w", d.Name, err)
+		}
+		// The marker lets a later Plan tell this generated comment from a
+		// hand-written one and notice when it goes stale.
+		text = strings.TrimRight(text, "\n") + "\n\n" + commentsig.BuildMarker(d.CurrentSig)
+
+		switch {
+		case target.fn != nil:
+			out, err = docattach.AttachFuncDoc(fset, out, target.fn, text)
+		case target.gd != nil:
+			out, err = docattach.AttachGenDeclDoc(fset, out, target.gd, text)
+			if err == nil && target.gd.Tok == token.CONST {
+				out, err = attachConstValueHints(filename, out, d.Name)
+			}
+		case target.field != nil:
+			out, err = docattach.AttachFieldDoc(fset, out, target.field, text)
+		}
+		if err != nil {
+			return nil, report, fmt.Errorf("commenter: attaching doc for %s: %w", d.Name, err)
+		}
+	}
+	return out, report, nil
+}
+
+// attachConstValueHints re-evaluates src's constant values and attaches
+// a trailing "// <value>" comment to every spec in the const group named
+// by groupSpecName that has at least one inferred value not already
+// suppressed (see constval.Annotation.Suppressed). A single AttachValueComment
+// call replaces a spec's whole trailing comment rather than appending to
+// it, so a multi-name spec (e.g. "const a, b = 1+1, 2+2") gets exactly
+// one call with every one of its names' hints combined into one comment
+// (e.g. "a=2, b=4"), instead of one clobbering call per name. It
+// re-evaluates rather than reusing an earlier Plan because every prior
+// edit has shifted positions.
+func attachConstValueHints(filename string, src []byte, groupSpecName string) ([]byte, error) {
+	anns, err := constval.EvaluateFile(filename, src)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating constant values: %w", err)
+	}
+	annByName := map[string]constval.Annotation{}
+	for _, a := range anns {
+		if a.Ok && !a.Suppressed {
+			annByName[a.Name] = a
+		}
+	}
+
+	_, file, err := reparse(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	gd := findGenDecl(file, groupSpecName)
+	if gd == nil {
+		return src, nil
+	}
+	var specNames [][]string
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		var names []string
+		for _, n := range vs.Names {
+			names = append(names, n.Name)
+		}
+		specNames = append(specNames, names)
+	}
+
+	out := src
+	for _, names := range specNames {
+		text := combinedValueHint(names, annByName)
+		if text == "" {
+			continue
+		}
+		fset, file, err := reparse(filename, out)
+		if err != nil {
+			return nil, err
+		}
+		gd := findGenDecl(file, groupSpecName)
+		if gd == nil {
+			continue
+		}
+		spec := findValueSpec(gd, names[0])
+		if spec == nil {
+			continue
+		}
+		out, err = docattach.AttachValueComment(fset, out, spec, text)
+		if err != nil {
+			return nil, fmt.Errorf("attaching value hint for %s: %w", names[0], err)
+		}
+	}
+	return out, nil
+}
+
+// combinedValueHint builds the trailing comment text for a single
+// ValueSpec's names: the bare value for a single-name spec (matching the
+// gopls-style hint constval was built to mimic), or "name=value, ..." for
+// every name in a multi-name spec that has a usable annotation, since
+// AttachValueComment is called once per spec and can't tell which name
+// each part of a bare value belongs to. Returns "" if no name in names
+// has a usable annotation.
+func combinedValueHint(names []string, annByName map[string]constval.Annotation) string {
+	var parts []string
+	for _, n := range names {
+		a, ok := annByName[n]
+		if !ok {
+			continue
+		}
+		parts = append(parts, n+"="+a.Text)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if len(names) == 1 {
+		return annByName[names[0]].Text
+	}
+	return strings.Join(parts, ", ")
+}
+
+func reparse(filename string, src []byte) (*token.FileSet, *ast.File, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("commenter: reparsing %s: %w", filename, err)
+	}
+	return fset, file, nil
+}
+
+// findDecl returns the top-level FuncDecl or GenDecl that commentsig.Plan
+// would report under name (see commentsig's declCandidates), or nil. It
+// doesn't look inside interface types; use findTarget for that.
+func findDecl(file *ast.File, name string) ast.Decl {
+	for _, d := range file.Decls {
+		switch decl := d.(type) {
+		case *ast.FuncDecl:
+			if commentsig.FuncDeclKey(decl) == name {
+				return decl
+			}
+		case *ast.GenDecl:
+			if genDeclName(decl) == name {
+				return decl
+			}
+		}
+	}
+	return nil
+}
+
+// target is the AST node a Decision resolves to: exactly one field is
+// set. commentsig.Plan reports interface methods alongside top-level
+// declarations under a single Decision.Name namespace, so resolving a
+// name requires checking all three shapes.
+type target struct {
+	fn    *ast.FuncDecl
+	gd    *ast.GenDecl
+	field *ast.Field
+}
+
+// findTarget resolves the declaration or interface method that
+// commentsig.Plan reported under name.
+func findTarget(file *ast.File, name string) target {
+	if decl := findDecl(file, name); decl != nil {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			return target{fn: d}
+		case *ast.GenDecl:
+			return target{gd: d}
+		}
+	}
+	if field := findInterfaceMethod(file, name); field != nil {
+		return target{field: field}
+	}
+	return target{}
+}
+
+// findInterfaceMethod finds the *ast.Field for the interface method named
+// "<Interface>.<Method>" (see commentsig's interfaceMethodCandidates).
+func findInterfaceMethod(file *ast.File, name string) *ast.Field {
+	for _, d := range file.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			for _, f := range it.Methods.List {
+				for _, n := range f.Names {
+					if ts.Name.Name+"."+n.Name == name {
+						return f
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func findGenDecl(file *ast.File, specName string) *ast.GenDecl {
+	if d, ok := findDecl(file, specName).(*ast.GenDecl); ok {
+		return d
+	}
+	return nil
+}
+
+func findValueSpec(gd *ast.GenDecl, name string) *ast.ValueSpec {
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, n := range vs.Names {
+			if n.Name == name {
+				return vs
+			}
+		}
+	}
+	return nil
+}
+
+// genDeclName mirrors commentsig's unexported helper of the same name:
+// the name Plan reports for a GenDecl is its first spec's name.
+func genDeclName(d *ast.GenDecl) string {
+	if len(d.Specs) == 0 {
+		return ""
+	}
+	switch s := d.Specs[0].(type) {
+	case *ast.TypeSpec:
+		return s.Name.Name
+	case *ast.ValueSpec:
+		if len(s.Names) > 0 {
+			return s.Names[0].Name
+		}
+	}
+	return ""
+}
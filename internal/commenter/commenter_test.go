@@ -0,0 +1,216 @@
+package commenter
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelikhan/action-continuous-comments/internal/commentsig"
+)
+
+// fakeGenerator stands in for the LLM call a real action would make: it
+// returns deterministic text that always starts with the declaration's
+// name, satisfying the one convention docattach enforces.
+type fakeGenerator struct{}
+
+func (fakeGenerator) Doc(name string) (string, error) {
+	// A method's name is reported as "<ReceiverType>.<Method>" (see
+	// commentsig.FuncDeclKey) so methods of the same name on different
+	// types don't collide; the doc text itself must still start with
+	// the bare method name, per Go convention.
+	bare := name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		bare = name[i+1:]
+	}
+	return fmt.Sprintf("%s is a generated declaration for the test fixture.", bare), nil
+}
+
+func fixturePath(t *testing.T) string {
+	t.Helper()
+	return filepath.Join("..", "..", "test", "go-without-docs.go")
+}
+
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(fixturePath(t))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return data
+}
+
+// TestRunDryRunReportsWithoutModifying exercises
+// commentstyle.Detect -> commentsig.Plan without touching the source,
+// the path a `dry-run` action input takes.
+func TestRunDryRunReportsWithoutModifying(t *testing.T) {
+	src := readFixture(t)
+	out, report, err := Run(fixturePath(t), src, Options{Policy: commentsig.PolicyStale, DryRun: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if string(out) != string(src) {
+		t.Error("DryRun must not modify the source")
+	}
+	if report.Total == 0 || report.WouldRegenerate != report.Total {
+		t.Fatalf("report = %+v, want every declaration in the comment-free fixture flagged", report)
+	}
+}
+
+// TestRunEndToEnd is the integration test tying every package in this
+// module together: commentstyle detects the file is Go, commentsig.Plan
+// decides every declaration needs a comment, a Generator stands in for
+// the LLM, docattach places each comment on its declaration's Doc slot,
+// and for the Color const group constval's inferred values are attached
+// as trailing per-spec hints (except PI, whose literal already matches
+// its folded value and so is left alone).
+func TestRunEndToEnd(t *testing.T) {
+	src := readFixture(t)
+	out, report, err := Run(fixturePath(t), src, Options{
+		Policy:    commentsig.PolicyStale,
+		Generator: fakeGenerator{},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if string(out) == string(src) {
+		t.Fatal("expected the source to change")
+	}
+	if report.WouldRegenerate != report.Total {
+		t.Fatalf("report = %+v, want every declaration flagged for regeneration", report)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fixturePath(t), out, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing Run's output: %v\n--- output ---\n%s", err, out)
+	}
+
+	fn := findDecl(file, "AddNumbers").(*ast.FuncDecl)
+	if fn.Doc == nil || !strings.HasPrefix(fn.Doc.Text(), "AddNumbers ") {
+		t.Errorf("AddNumbers.Doc = %q", fn.Doc.Text())
+	}
+
+	move := findDecl(file, "*Point.Move").(*ast.FuncDecl)
+	if move.Doc == nil || !strings.HasPrefix(move.Doc.Text(), "Move ") {
+		t.Errorf("Move.Doc = %q", move.Doc.Text())
+	}
+
+	// Point and Color both define a String method; Run must generate
+	// and attach a doc for each independently rather than processing
+	// one of them twice and leaving the other bare.
+	pointString := findDecl(file, "Point.String").(*ast.FuncDecl)
+	if pointString.Doc == nil || !strings.HasPrefix(pointString.Doc.Text(), "String ") {
+		t.Errorf("Point.String.Doc = %q", pointString.Doc.Text())
+	}
+	colorString := findDecl(file, "Color.String").(*ast.FuncDecl)
+	if colorString.Doc == nil || !strings.HasPrefix(colorString.Doc.Text(), "String ") {
+		t.Errorf("Color.String.Doc = %q", colorString.Doc.Text())
+	}
+
+	colors := findDecl(file, "Red").(*ast.GenDecl)
+	if colors.Doc == nil || !strings.HasPrefix(colors.Doc.Text(), "Red ") {
+		t.Errorf("Color group Doc = %q", colors.Doc.Text())
+	}
+	wantValues := map[string]string{"Red": "0", "Green": "1", "Blue": "2"}
+	for _, spec := range colors.Specs {
+		vs := spec.(*ast.ValueSpec)
+		name := vs.Names[0].Name
+		want, ok := wantValues[name]
+		if !ok {
+			continue
+		}
+		if vs.Comment == nil {
+			t.Errorf("%s: expected a trailing value comment", name)
+			continue
+		}
+		if got := strings.TrimSpace(vs.Comment.Text()); got != want {
+			t.Errorf("%s value comment = %q, want %q", name, got, want)
+		}
+	}
+
+	pi := findDecl(file, "PI").(*ast.GenDecl)
+	piSpec := pi.Specs[0].(*ast.ValueSpec)
+	if piSpec.Comment != nil {
+		t.Errorf("PI: expected no value comment since its literal already matches its folded value, got %q", piSpec.Comment.Text())
+	}
+
+	// The fixture's Drawable interface is exactly the shape chunk0-4 was
+	// asked to support: Run must attach a doc to the Draw method's own
+	// Field.Doc, not just to the interface's GenDecl.
+	drawable := findDecl(file, "Drawable").(*ast.GenDecl)
+	if drawable.Doc == nil || !strings.HasPrefix(drawable.Doc.Text(), "Drawable ") {
+		t.Errorf("Drawable.Doc = %q", drawable.Doc.Text())
+	}
+	draw := findInterfaceMethodField(file, "Drawable", "Draw")
+	if draw == nil {
+		t.Fatal("Draw method not found after Run")
+	}
+	if draw.Doc == nil || !strings.HasPrefix(draw.Doc.Text(), "Draw ") {
+		t.Errorf("Draw.Doc = %q, want a comment starting with \"Draw \"", draw.Doc.Text())
+	}
+}
+
+func findInterfaceMethodField(file *ast.File, iface, method string) *ast.Field {
+	gd, ok := findDecl(file, iface).(*ast.GenDecl)
+	if !ok {
+		return nil
+	}
+	ts, ok := gd.Specs[0].(*ast.TypeSpec)
+	if !ok {
+		return nil
+	}
+	it, ok := ts.Type.(*ast.InterfaceType)
+	if !ok {
+		return nil
+	}
+	for _, f := range it.Methods.List {
+		for _, n := range f.Names {
+			if n.Name == method {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// TestRunCombinesMultiNameConstValueHints guards against the regression
+// where a multi-name const spec's names each triggered their own
+// AttachValueComment call, clobbering every earlier name's hint since
+// they share one *ast.ValueSpec's trailing comment: only the last name's
+// value would survive.
+func TestRunCombinesMultiNameConstValueHints(t *testing.T) {
+	const src = "package sample\n\nconst a, b = 1+1, 2+2\n"
+	out, _, err := Run("sample.go", []byte(src), Options{
+		Policy:    commentsig.PolicyStale,
+		Generator: fakeGenerator{},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", out, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing Run's output: %v\n--- output ---\n%s", err, out)
+	}
+	gd := findDecl(file, "a").(*ast.GenDecl)
+	spec := gd.Specs[0].(*ast.ValueSpec)
+	if spec.Comment == nil {
+		t.Fatal("expected a combined trailing value comment")
+	}
+	got := strings.TrimSpace(spec.Comment.Text())
+	if !strings.Contains(got, "a=2") || !strings.Contains(got, "b=4") {
+		t.Errorf("value comment = %q, want both a=2 and b=4", got)
+	}
+}
+
+func TestRunRejectsUnsupportedLanguage(t *testing.T) {
+	if _, _, err := Run("sample.py", []byte("def f():\n    pass\n"), Options{Policy: commentsig.PolicyStale, Generator: fakeGenerator{}}); err == nil {
+		t.Fatal("expected an error for a language commenter doesn't support yet")
+	}
+}
@@ -0,0 +1,321 @@
+package commentsig
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelikhan/action-continuous-comments/internal/docattach"
+)
+
+func TestShouldRegenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		existing Existing
+		sig      string
+		want     bool
+	}{
+		{"no comment always generates", PolicyStale, Existing{Present: false}, "abc", true},
+		{"keep wins over always", PolicyAlways, Existing{Present: true, Keep: true, Sig: "old"}, "new", false},
+		{"hand-written comment untouched", PolicyAlways, Existing{Present: true, Sig: ""}, "new", false},
+		{"missing policy skips existing", PolicyMissing, Existing{Present: true, Sig: "old"}, "new", false},
+		{"stale policy regenerates on mismatch", PolicyStale, Existing{Present: true, Sig: "old"}, "new", true},
+		{"stale policy leaves fresh alone", PolicyStale, Existing{Present: true, Sig: "same"}, "same", false},
+		{"always policy regenerates fresh too", PolicyAlways, Existing{Present: true, Sig: "same"}, "same", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShouldRegenerate(tt.policy, tt.existing, tt.sig)
+			if got != tt.want {
+				t.Errorf("ShouldRegenerate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarker(t *testing.T) {
+	e := ParseMarker("AddNumbers adds two integers.\n\ncontinuous-comments:sig=deadbeefcafe")
+	if e.Sig != "deadbeefcafe" {
+		t.Errorf("Sig = %q, want deadbeefcafe", e.Sig)
+	}
+	if e.Keep {
+		t.Error("Keep = true, want false")
+	}
+
+	e = ParseMarker("Hand written, do not touch.\nkeep")
+	if !e.Keep {
+		t.Error("expected Keep = true")
+	}
+	if e.Sig != "" {
+		t.Errorf("Sig = %q, want empty", e.Sig)
+	}
+}
+
+func TestPlanFixtureHasNoExistingComments(t *testing.T) {
+	fset := token.NewFileSet()
+	path := filepath.Join("..", "..", "test", "go-without-docs.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	report, err := Plan(fset, file, PolicyStale)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if report.Total == 0 {
+		t.Fatal("expected at least one candidate declaration")
+	}
+	if report.WouldRegenerate != report.Total {
+		t.Errorf("WouldRegenerate = %d, want %d (fixture has no doc comments)", report.WouldRegenerate, report.Total)
+	}
+}
+
+// TestPlanReportsInterfaceMethods guards against the regression where
+// Plan only ever inspected top-level declarations, so an interface
+// method's own Field.Doc was never a regeneration candidate: the doc
+// commenter.Run attached to `type Drawable interface { ... }` covered the
+// interface itself but Draw() was left undocumented forever.
+func TestPlanReportsInterfaceMethods(t *testing.T) {
+	const src = `package sample
+
+// Drawable can render itself.
+type Drawable interface {
+	Draw()
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	report, err := Plan(fset, file, PolicyStale)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	byName := map[string]Decision{}
+	for _, d := range report.Decisions {
+		byName[d.Name] = d
+	}
+	if _, ok := byName["Drawable"]; !ok {
+		t.Error("expected a Decision for the interface type itself")
+	}
+	draw, ok := byName["Drawable.Draw"]
+	if !ok {
+		t.Fatal(`expected a Decision named "Drawable.Draw" for the interface method`)
+	}
+	if !draw.Regenerate {
+		t.Error("Drawable.Draw: expected regenerate, it has no doc comment")
+	}
+}
+
+func TestPlanRespectsStaleAndKeepMarkers(t *testing.T) {
+	const src = `package sample
+
+// AddNumbers adds two integers.
+//
+// continuous-comments:sig=000000000000
+func AddNumbers(a, b int) int {
+	return a + b
+}
+
+// Multiply multiplies two integers.
+//
+// keep
+func Multiply(a, b int) int {
+	return a * b
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	report, err := Plan(fset, file, PolicyStale)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	byName := map[string]Decision{}
+	for _, d := range report.Decisions {
+		byName[d.Name] = d
+	}
+
+	add := byName["AddNumbers"]
+	if !add.Regenerate {
+		t.Error("AddNumbers: expected regenerate, its stored sig is stale")
+	}
+	mul := byName["Multiply"]
+	if mul.Regenerate {
+		t.Error("Multiply: expected no regenerate, it carries a keep directive")
+	}
+}
+
+// findGenDecl returns the GenDecl declaring specName, and findValueSpec /
+// findInterfaceMethod drill further into it; these mirror the finders in
+// the docattach tests, kept local here to avoid a test-only export.
+func findGenDecl(file *ast.File, specName string) *ast.GenDecl {
+	for _, d := range file.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.Name == specName {
+					return gd
+				}
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if n.Name == specName {
+						return gd
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func findValueSpec(gd *ast.GenDecl, name string) *ast.ValueSpec {
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, n := range vs.Names {
+			if n.Name == name {
+				return vs
+			}
+		}
+	}
+	return nil
+}
+
+func findInterfaceMethod(file *ast.File, iface, method string) *ast.Field {
+	gd := findGenDecl(file, iface)
+	if gd == nil {
+		return nil
+	}
+	ts, ok := gd.Specs[0].(*ast.TypeSpec)
+	if !ok {
+		return nil
+	}
+	it, ok := ts.Type.(*ast.InterfaceType)
+	if !ok {
+		return nil
+	}
+	for _, f := range it.Methods.List {
+		for _, n := range f.Names {
+			if n.Name == method {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// TestHashNodeIgnoresNestedValueComments guards against the regression
+// where hashing a const group changed after attaching the per-spec
+// "// <value>" hints that chunk0-2/chunk0-4 generate, which would make
+// this package regenerate (or desync from) a comment the moment it was
+// first written.
+func TestHashNodeIgnoresNestedValueComments(t *testing.T) {
+	path := filepath.Join("..", "..", "test", "go-without-docs.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+	before := findGenDecl(file, "Red")
+	sigBefore, err := HashNode(fset, before)
+	if err != nil {
+		t.Fatalf("HashNode before: %v", err)
+	}
+
+	out := src
+	for name, value := range map[string]string{"Red": "0", "Green": "1", "Blue": "2"} {
+		fset2 := token.NewFileSet()
+		file2, err := parser.ParseFile(fset2, path, out, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("reparsing after attaching %s: %v", name, err)
+		}
+		spec := findValueSpec(findGenDecl(file2, "Red"), name)
+		out, err = docattach.AttachValueComment(fset2, out, spec, value)
+		if err != nil {
+			t.Fatalf("AttachValueComment(%s): %v", name, err)
+		}
+	}
+
+	fset3 := token.NewFileSet()
+	file3, err := parser.ParseFile(fset3, path, out, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("reparsing final: %v", err)
+	}
+	after := findGenDecl(file3, "Red")
+	sigAfter, err := HashNode(fset3, after)
+	if err != nil {
+		t.Fatalf("HashNode after: %v", err)
+	}
+
+	if sigBefore != sigAfter {
+		t.Errorf("signature changed after attaching value comments: before=%s after=%s", sigBefore, sigAfter)
+	}
+}
+
+// TestHashNodeIgnoresNestedFieldDoc guards against the same regression
+// for an interface method's per-field Doc comment.
+func TestHashNodeIgnoresNestedFieldDoc(t *testing.T) {
+	path := filepath.Join("..", "..", "test", "go-without-docs.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+	before := findGenDecl(file, "Drawable")
+	sigBefore, err := HashNode(fset, before)
+	if err != nil {
+		t.Fatalf("HashNode before: %v", err)
+	}
+
+	field := findInterfaceMethod(file, "Drawable", "Draw")
+	out, err := docattach.AttachFieldDoc(fset, src, field, "Draw renders the shape.")
+	if err != nil {
+		t.Fatalf("AttachFieldDoc: %v", err)
+	}
+
+	fset2 := token.NewFileSet()
+	file2, err := parser.ParseFile(fset2, path, out, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("reparsing after attach: %v", err)
+	}
+	after := findGenDecl(file2, "Drawable")
+	sigAfter, err := HashNode(fset2, after)
+	if err != nil {
+		t.Fatalf("HashNode after: %v", err)
+	}
+
+	if sigBefore != sigAfter {
+		t.Errorf("signature changed after attaching a field doc: before=%s after=%s", sigBefore, sigAfter)
+	}
+}
@@ -0,0 +1,183 @@
+package commentsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// markerPrefix introduces the trailing signature marker line this package
+// adds to a generated comment.
+const markerPrefix = "continuous-comments:sig="
+
+// keepDirective is a standalone comment line that opts a declaration out
+// of regeneration entirely.
+const keepDirective = "keep"
+
+var markerRE = regexp.MustCompile(`^` + regexp.QuoteMeta(markerPrefix) + `([0-9a-f]+)$`)
+
+// BuildMarker returns the marker line to append to a generated comment
+// for a declaration whose body hashes to sig. Callers render it as a
+// comment in the target language, e.g. prefixing it with "// ".
+func BuildMarker(sig string) string {
+	return markerPrefix + sig
+}
+
+// ParseMarker scans every line of a comment's text (no comment
+// delimiters) for a continuous-comments:sig marker or a "keep"
+// directive standing on a line of its own.
+func ParseMarker(text string) Existing {
+	e := Existing{Present: true}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == keepDirective {
+			e.Keep = true
+			continue
+		}
+		if m := markerRE.FindStringSubmatch(line); m != nil {
+			e.Sig = m[1]
+		}
+	}
+	return e
+}
+
+// HashNode returns a short, stable hex signature for node's body,
+// ignoring source whitespace and every comment reachable from it: node
+// is deep-copied with every Doc/Comment comment group it or its nested
+// specs and fields carry cleared, the copy is printed through
+// go/printer, every run of whitespace in the output is collapsed to a
+// single space, and the result is hashed. Two declarations that differ
+// only in formatting, in their own doc comment, or in a nested comment
+// such as a const spec's trailing value hint or an interface method's
+// doc, hash identically. Whitespace inside string literals is collapsed
+// too.
+func HashNode(fset *token.FileSet, node ast.Node) (string, error) {
+	node = stripComments(node)
+	var buf bytes.Buffer
+	// go/printer can print a *ast.Field in the context of its enclosing
+	// FieldList but rejects one handed to it standalone (as an interface
+	// method Decision's node is here), so render it as its name plus
+	// signature instead.
+	if field, ok := node.(*ast.Field); ok {
+		if err := printFieldSignature(&buf, fset, field); err != nil {
+			return "", fmt.Errorf("commentsig: printing node: %w", err)
+		}
+	} else if err := printer.Fprint(&buf, fset, node); err != nil {
+		return "", fmt.Errorf("commentsig: printing node: %w", err)
+	}
+	// go/printer keeps blank lines and some line breaks from the source.
+	normalized := strings.Join(strings.Fields(buf.String()), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// printFieldSignature renders an interface method field as "Name Type",
+// e.g. "Draw func()", since go/printer only knows how to print a *ast.Field
+// as part of its enclosing FieldList.
+func printFieldSignature(buf *bytes.Buffer, fset *token.FileSet, field *ast.Field) error {
+	for i, name := range field.Names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(name.Name)
+	}
+	if field.Type == nil {
+		return nil
+	}
+	buf.WriteByte(' ')
+	return printer.Fprint(buf, fset, field.Type)
+}
+
+// stripComments returns a copy of node with every Doc/Comment comment
+// group cleared, recursively through the declaration shapes this
+// package cares about: a GenDecl's specs (const/var/type groups,
+// including per-spec trailing value hints) and a type spec's interface
+// or struct field list (including per-method docs). This keeps
+// HashNode's printed output free of any comment a previous run of the
+// commenter may have attached, at any depth, not just at the top level.
+func stripComments(node ast.Node) ast.Node {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		cp := *n
+		cp.Doc = nil
+		return &cp
+	case *ast.GenDecl:
+		cp := *n
+		cp.Doc = nil
+		cp.Specs = make([]ast.Spec, len(n.Specs))
+		for i, s := range n.Specs {
+			cp.Specs[i] = stripSpecComments(s)
+		}
+		return &cp
+	case *ast.Field:
+		cp := *n
+		cp.Doc = nil
+		cp.Comment = nil
+		return &cp
+	default:
+		return node
+	}
+}
+
+// stripSpecComments clears a GenDecl spec's own Doc/Comment and, for a
+// type spec, recurses into its interface or struct field list.
+func stripSpecComments(spec ast.Spec) ast.Spec {
+	switch s := spec.(type) {
+	case *ast.ValueSpec:
+		cp := *s
+		cp.Doc = nil
+		cp.Comment = nil
+		return &cp
+	case *ast.TypeSpec:
+		cp := *s
+		cp.Doc = nil
+		cp.Comment = nil
+		cp.Type = stripTypeComments(s.Type)
+		return &cp
+	case *ast.ImportSpec:
+		cp := *s
+		cp.Doc = nil
+		cp.Comment = nil
+		return &cp
+	default:
+		return spec
+	}
+}
+
+// stripTypeComments recurses into the field lists of interface and
+// struct type expressions, clearing each field's Doc/Comment.
+func stripTypeComments(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.InterfaceType:
+		cp := *e
+		cp.Methods = stripFieldListComments(e.Methods)
+		return &cp
+	case *ast.StructType:
+		cp := *e
+		cp.Fields = stripFieldListComments(e.Fields)
+		return &cp
+	default:
+		return expr
+	}
+}
+
+func stripFieldListComments(fl *ast.FieldList) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	cp := *fl
+	cp.List = make([]*ast.Field, len(fl.List))
+	for i, f := range fl.List {
+		fc := *f
+		fc.Doc = nil
+		fc.Comment = nil
+		cp.List[i] = &fc
+	}
+	return &cp
+}
@@ -0,0 +1,164 @@
+package commentsig
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Decision is the regeneration verdict for a single declaration: a
+// top-level function, type, const, or var, or a method declared inside
+// an interface type.
+type Decision struct {
+	// Name identifies the declaration: the bare identifier for a
+	// function, type, const, or var; "<ReceiverType>.<Method>" (e.g.
+	// "*Point.Move") for a method, since a type can define methods of
+	// the same name on itself and a pointer to itself, or share a
+	// method name with another type entirely (see FuncDeclKey); or
+	// "<Interface>.<Method>" for a method declared inside an interface
+	// type, e.g. "Drawable.Draw".
+	Name       string
+	Pos        token.Position
+	CurrentSig string
+	Existing   Existing
+	Regenerate bool
+}
+
+// Report summarizes a Plan run, e.g. for a `--dry-run` action input.
+type Report struct {
+	Total           int
+	WouldRegenerate int
+	Decisions       []Decision
+}
+
+// Plan walks file's top-level function, type, const and var declarations,
+// plus every method declared inside an interface type, and for each
+// decides under policy whether its doc comment would be (re)generated.
+// It does not modify file; callers use the report to log a dry-run
+// summary or to drive the actual regeneration pass.
+func Plan(fset *token.FileSet, file *ast.File, policy Policy) (Report, error) {
+	var report Report
+	for _, decl := range file.Decls {
+		for _, c := range declCandidates(decl) {
+			sig, err := HashNode(fset, c.node)
+			if err != nil {
+				return report, err
+			}
+			existing := existingFromDoc(c.doc)
+			d := Decision{
+				Name:       c.name,
+				Pos:        fset.Position(c.node.Pos()),
+				CurrentSig: sig,
+				Existing:   existing,
+				Regenerate: ShouldRegenerate(policy, existing, sig),
+			}
+			report.Total++
+			if d.Regenerate {
+				report.WouldRegenerate++
+			}
+			report.Decisions = append(report.Decisions, d)
+		}
+	}
+	return report, nil
+}
+
+// candidate is a single declaration Plan considers for a generated doc
+// comment.
+type candidate struct {
+	name string
+	doc  *ast.CommentGroup
+	node ast.Node
+}
+
+// declCandidates extracts every candidate top-level declaration (and, for
+// an interface type, its methods) nested inside decl. It returns nil for
+// declarations that aren't candidates for a generated comment (e.g.
+// import specs).
+func declCandidates(decl ast.Decl) []candidate {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return []candidate{{FuncDeclKey(d), d.Doc, d}}
+	case *ast.GenDecl:
+		switch d.Tok {
+		case token.TYPE, token.CONST, token.VAR:
+			return append([]candidate{{genDeclName(d), d.Doc, d}}, interfaceMethodCandidates(d)...)
+		default:
+			return nil
+		}
+	default:
+		return nil
+	}
+}
+
+// interfaceMethodCandidates returns one candidate per named method
+// declared inside any interface type spec of d, keyed
+// "<Interface>.<Method>" (mirroring FuncDeclKey's receiver-qualification,
+// since an interface method and a type's own method can share a bare
+// name). Embedded interfaces (fields with no Names) aren't methods and
+// are skipped.
+func interfaceMethodCandidates(d *ast.GenDecl) []candidate {
+	var out []candidate
+	for _, spec := range d.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		it, ok := ts.Type.(*ast.InterfaceType)
+		if !ok {
+			continue
+		}
+		for _, f := range it.Methods.List {
+			if len(f.Names) == 0 {
+				continue
+			}
+			out = append(out, candidate{ts.Name.Name + "." + f.Names[0].Name, f.Doc, f})
+		}
+	}
+	return out
+}
+
+// FuncDeclKey returns the identifier that uniquely names d among a
+// file's top-level declarations: the bare function name, or
+// "<ReceiverType>.<Name>" for a method, so that e.g. Point.String and
+// Color.String (two distinct methods that happen to share a name) don't
+// collide the way d.Name.Name alone would.
+func FuncDeclKey(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return d.Name.Name
+	}
+	return recvTypeName(d.Recv.List[0].Type) + "." + d.Name.Name
+}
+
+func recvTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + recvTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return recvTypeName(t.X)
+	default:
+		return "?"
+	}
+}
+
+func genDeclName(d *ast.GenDecl) string {
+	if len(d.Specs) == 0 {
+		return ""
+	}
+	switch s := d.Specs[0].(type) {
+	case *ast.TypeSpec:
+		return s.Name.Name
+	case *ast.ValueSpec:
+		if len(s.Names) > 0 {
+			return s.Names[0].Name
+		}
+	}
+	return ""
+}
+
+func existingFromDoc(doc *ast.CommentGroup) Existing {
+	if doc == nil {
+		return Existing{Present: false}
+	}
+	return ParseMarker(doc.Text())
+}
@@ -0,0 +1,64 @@
+// Package commentsig decides, for a Go declaration, whether a previously
+// generated doc comment needs to be regenerated. It hashes the
+// declaration's body (ignoring whitespace and comments) and stores the
+// hash inside the managed comment as a trailing "continuous-comments:sig="
+// marker, so later runs can tell a stale comment from a fresh one and
+// leave hand-written comments alone.
+package commentsig
+
+// Policy controls which existing comments are eligible for regeneration.
+// It corresponds to the action's `update-policy` input.
+type Policy string
+
+const (
+	// PolicyAlways regenerates every managed comment, even if its
+	// signature still matches the current declaration.
+	PolicyAlways Policy = "always"
+	// PolicyStale regenerates only managed comments whose signature no
+	// longer matches the declaration (the default).
+	PolicyStale Policy = "stale"
+	// PolicyMissing only fills in declarations that have no comment at
+	// all; it never touches a declaration that already has one, managed
+	// or not.
+	PolicyMissing Policy = "missing"
+)
+
+// Existing summarizes the comment already attached to a declaration, as
+// parsed by ParseMarker.
+type Existing struct {
+	// Present reports whether the declaration has a leading doc comment
+	// at all.
+	Present bool
+	// Keep reports whether the comment carries a "// keep" opt-out
+	// directive, which always wins regardless of Policy.
+	Keep bool
+	// Sig is the signature recorded in the comment's
+	// continuous-comments:sig marker, or "" if the comment has none
+	// (which marks it as hand-written rather than generated).
+	Sig string
+}
+
+// ShouldRegenerate reports whether the comment described by existing
+// should be (re)generated for a declaration whose current body hashes to
+// currentSig, under policy.
+func ShouldRegenerate(policy Policy, existing Existing, currentSig string) bool {
+	if existing.Keep {
+		return false
+	}
+	if !existing.Present {
+		return true
+	}
+	if existing.Sig == "" {
+		// A comment with no marker was written by a human, not by a
+		// previous run of this action. Never overwrite it.
+		return false
+	}
+	switch policy {
+	case PolicyAlways:
+		return true
+	case PolicyMissing:
+		return false
+	default: // PolicyStale and any unrecognized value fall back to it.
+		return existing.Sig != currentSig
+	}
+}
@@ -0,0 +1,134 @@
+package commentstyle
+
+import "strings"
+
+// Comment is a comment found in a source file by Strip.
+type Comment struct {
+	// Text is the comment body, excluding its delimiters.
+	Text string
+	// Block reports whether this was a block comment as opposed to a
+	// line comment.
+	Block bool
+	// Offset is the byte offset of the comment's opening delimiter in
+	// the original source.
+	Offset int
+}
+
+// Strip removes every comment recognized by style from src and returns the
+// remaining code together with the comments that were removed, in source
+// order. It is intentionally simple: it tracks string literals opened by
+// style's quote characters (see Style.QuoteChars; single and double
+// quotes unless the style overrides them) so that comment-like sequences
+// inside strings are left alone, but it does not otherwise understand
+// the target language's grammar.
+func Strip(src string, style Style) (code string, comments []Comment) {
+	var out strings.Builder
+	var quote byte
+	n := len(src)
+	for i := 0; i < n; {
+		c := src[i]
+
+		if quote != 0 {
+			out.WriteByte(c)
+			if c == '\\' && i+1 < n {
+				out.WriteByte(src[i+1])
+				i += 2
+				continue
+			}
+			if c == quote {
+				quote = 0
+			}
+			i++
+			continue
+		}
+
+		if strings.IndexByte(style.quoteChars(), c) >= 0 {
+			quote = c
+			out.WriteByte(c)
+			i++
+			continue
+		}
+
+		if start, end, ok := matchBlock(src, i, style); ok {
+			closeIdx := strings.Index(src[i+len(start):], end)
+			var body string
+			var next int
+			if closeIdx < 0 {
+				body = src[i+len(start):]
+				next = n
+			} else {
+				body = src[i+len(start) : i+len(start)+closeIdx]
+				next = i + len(start) + closeIdx + len(end)
+			}
+			comments = append(comments, Comment{Text: body, Block: true, Offset: i})
+			i = next
+			continue
+		}
+
+		if prefix, ok := matchLine(src, i, style); ok {
+			nl := strings.IndexByte(src[i+len(prefix):], '\n')
+			var body string
+			var next int
+			if nl < 0 {
+				body = src[i+len(prefix):]
+				next = n
+			} else {
+				body = src[i+len(prefix) : i+len(prefix)+nl]
+				next = i + len(prefix) + nl // leave the newline in the code
+			}
+			comments = append(comments, Comment{Text: body, Block: false, Offset: i})
+			i = next
+			continue
+		}
+
+		out.WriteByte(c)
+		i++
+	}
+	return out.String(), comments
+}
+
+func matchBlock(src string, i int, style Style) (start, end string, ok bool) {
+	for _, b := range style.Blocks {
+		if strings.HasPrefix(src[i:], b.Start) {
+			return b.Start, b.End, true
+		}
+	}
+	return "", "", false
+}
+
+func matchLine(src string, i int, style Style) (prefix string, ok bool) {
+	for _, p := range style.LinePrefixes {
+		if strings.HasPrefix(src[i:], p) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// RenderLine renders text as a line comment using style's first
+// line-comment prefix, one prefix per line of text.
+func RenderLine(style Style, text string) string {
+	if !style.HasLineComments() {
+		return RenderBlock(style, text)
+	}
+	prefix := style.LinePrefixes[0]
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = prefix
+		} else {
+			lines[i] = prefix + " " + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderBlock renders text as a single block comment using style's first
+// block-comment delimiter pair.
+func RenderBlock(style Style, text string) string {
+	if !style.HasBlockComments() {
+		return RenderLine(style, text)
+	}
+	b := style.Blocks[0]
+	return b.Start + " " + text + " " + b.End
+}
@@ -0,0 +1,44 @@
+package commentstyle
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DetectConfig lets callers override language detection for extensions the
+// built-in registry doesn't know about, or for extensions that are
+// ambiguous (e.g. ".h" is C by default but a project may want C++).
+// Overrides maps a file extension (with or without the leading dot) to a
+// canonical language name understood by Lookup.
+type DetectConfig struct {
+	Overrides map[string]string
+}
+
+// normalizeExt lowercases ext and ensures it starts with a dot.
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// Detect returns the comment style for path, first consulting cfg's
+// overrides and falling back to the built-in extension registry. It
+// reports false if the extension is unrecognized.
+func Detect(path string, cfg DetectConfig) (Style, bool) {
+	ext := normalizeExt(filepath.Ext(path))
+	if ext == "" {
+		return Style{}, false
+	}
+	for key, lang := range cfg.Overrides {
+		if normalizeExt(key) == ext {
+			return Lookup(lang)
+		}
+	}
+	lang, ok := extensionIndex[ext]
+	if !ok {
+		return Style{}, false
+	}
+	return Lookup(lang)
+}
@@ -0,0 +1,189 @@
+// Package commentstyle describes, per programming language, how comments
+// are spelled so the commenter pipeline can strip existing comments before
+// sending a file to the LLM and re-emit new ones in idiomatic style.
+package commentstyle
+
+import "strings"
+
+// Block describes a block comment's opening and closing delimiters, e.g.
+// "/*" and "*/" for C-family languages.
+type Block struct {
+	Start string
+	End   string
+}
+
+// Style describes the comment syntax for a single language. A language may
+// support several line-comment prefixes (PHP accepts both "//" and "#") and
+// several block-comment delimiter pairs, so both are slices.
+type Style struct {
+	// Name is the canonical, human-readable language name (e.g. "Go").
+	Name string
+	// Extensions lists the file extensions associated with this language,
+	// including the leading dot (e.g. ".go").
+	Extensions []string
+	// LinePrefixes lists the strings that start a line comment running to
+	// the end of the line. Empty if the language has no line comments.
+	LinePrefixes []string
+	// Blocks lists the delimiter pairs that start/end a block comment.
+	// Empty if the language has no block comments.
+	Blocks []Block
+	// QuoteChars lists the characters that open a string literal, so the
+	// scanner in comments.go doesn't mistake comment-like sequences
+	// inside strings for real comments. Defaults to `"'` (both double
+	// and single quotes) when left empty; languages such as Visual
+	// Basic, where a single quote is the line-comment marker rather
+	// than a string delimiter, override it.
+	QuoteChars string
+}
+
+// defaultQuoteChars is used by any Style that doesn't set QuoteChars.
+const defaultQuoteChars = `"'`
+
+// quoteChars returns the effective set of string-opening characters for s.
+func (s Style) quoteChars() string {
+	if s.QuoteChars != "" {
+		return s.QuoteChars
+	}
+	return defaultQuoteChars
+}
+
+// HasLineComments reports whether the style defines at least one
+// line-comment prefix.
+func (s Style) HasLineComments() bool {
+	return len(s.LinePrefixes) > 0
+}
+
+// HasBlockComments reports whether the style defines at least one
+// block-comment delimiter pair.
+func (s Style) HasBlockComments() bool {
+	return len(s.Blocks) > 0
+}
+
+// registry is the built-in set of language comment styles, keyed by the
+// canonical lowercase language name.
+var registry = map[string]*Style{}
+
+// extensionIndex maps a lowercase file extension (including the leading
+// dot) to the canonical lowercase language name that owns it.
+var extensionIndex = map[string]string{}
+
+func register(s Style) {
+	key := strings.ToLower(s.Name)
+	registry[key] = &s
+	for _, ext := range s.Extensions {
+		extensionIndex[strings.ToLower(ext)] = key
+	}
+}
+
+func init() {
+	cFamilyBlocks := []Block{{Start: "/*", End: "*/"}}
+
+	register(Style{
+		Name:         "Go",
+		Extensions:   []string{".go"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "Java",
+		Extensions:   []string{".java"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "C",
+		Extensions:   []string{".c", ".h"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "C++",
+		Extensions:   []string{".cc", ".cpp", ".cxx", ".hpp", ".hh"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "C#",
+		Extensions:   []string{".cs"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "JavaScript",
+		Extensions:   []string{".js", ".jsx", ".mjs", ".cjs"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "TypeScript",
+		Extensions:   []string{".ts", ".tsx"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "Python",
+		Extensions:   []string{".py"},
+		LinePrefixes: []string{"#"},
+	})
+	register(Style{
+		Name:         "Ruby",
+		Extensions:   []string{".rb"},
+		LinePrefixes: []string{"#"},
+		Blocks:       []Block{{Start: "=begin", End: "=end"}},
+	})
+	register(Style{
+		Name:         "PHP",
+		Extensions:   []string{".php"},
+		LinePrefixes: []string{"//", "#"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "Shell",
+		Extensions:   []string{".sh", ".bash"},
+		LinePrefixes: []string{"#"},
+	})
+	register(Style{
+		Name:         "Swift",
+		Extensions:   []string{".swift"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "Objective-C",
+		Extensions:   []string{".m", ".mm"},
+		LinePrefixes: []string{"//"},
+		Blocks:       cFamilyBlocks,
+	})
+	register(Style{
+		Name:         "Visual Basic",
+		Extensions:   []string{".vb"},
+		LinePrefixes: []string{"'"},
+		QuoteChars:   `"`,
+	})
+	register(Style{
+		Name:         "Common Lisp",
+		Extensions:   []string{".lisp", ".lsp", ".cl"},
+		LinePrefixes: []string{";"},
+		Blocks:       []Block{{Start: "#|", End: "|#"}},
+	})
+}
+
+// Lookup returns the registered style for the given canonical language
+// name (case-insensitive), e.g. Lookup("go") or Lookup("C++").
+func Lookup(language string) (Style, bool) {
+	s, ok := registry[strings.ToLower(language)]
+	if !ok {
+		return Style{}, false
+	}
+	return *s, true
+}
+
+// Languages returns the canonical names of every registered language, in
+// no particular order.
+func Languages() []string {
+	names := make([]string, 0, len(registry))
+	for _, s := range registry {
+		names = append(names, s.Name)
+	}
+	return names
+}
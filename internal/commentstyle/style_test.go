@@ -0,0 +1,95 @@
+package commentstyle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fixtures maps a language name to the sample source file that exercises it.
+// The files live under ../../test and intentionally contain no comments.
+var fixtures = map[string]string{
+	"Go":           "go-without-docs.go",
+	"Java":         "samples/java-without-docs.java",
+	"C":            "samples/c-without-docs.c",
+	"C++":          "samples/cpp-without-docs.cpp",
+	"C#":           "samples/csharp-without-docs.cs",
+	"JavaScript":   "samples/javascript-without-docs.js",
+	"TypeScript":   "samples/typescript-without-docs.ts",
+	"Python":       "samples/python-without-docs.py",
+	"Ruby":         "samples/ruby-without-docs.rb",
+	"PHP":          "samples/php-without-docs.php",
+	"Shell":        "samples/shell-without-docs.sh",
+	"Swift":        "samples/swift-without-docs.swift",
+	"Objective-C":  "samples/objc-without-docs.m",
+	"Visual Basic": "samples/vb-without-docs.vb",
+	"Common Lisp":  "samples/lisp-without-docs.lisp",
+}
+
+func TestFixturesCoverEveryLanguage(t *testing.T) {
+	for _, name := range Languages() {
+		if _, ok := fixtures[name]; !ok {
+			t.Errorf("no fixture registered for language %q", name)
+		}
+	}
+}
+
+func TestDetectAndRoundTrip(t *testing.T) {
+	for lang, file := range fixtures {
+		lang, file := lang, file
+		t.Run(lang, func(t *testing.T) {
+			path := filepath.Join("..", "..", "test", file)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+			src := string(data)
+
+			style, ok := Detect(path, DetectConfig{})
+			if !ok {
+				t.Fatalf("Detect(%q) failed", path)
+			}
+			if style.Name != lang {
+				t.Fatalf("Detect(%q) = %q, want %q", path, style.Name, lang)
+			}
+
+			// The fixtures ship without comments, so stripping them
+			// should be a no-op.
+			code, comments := Strip(src, style)
+			if code != src {
+				t.Fatalf("Strip on a comment-free fixture changed the code")
+			}
+			if len(comments) != 0 {
+				t.Fatalf("Strip found %d comments in a comment-free fixture", len(comments))
+			}
+
+			// Round-trip: render a comment, splice it in, strip it back
+			// out, and recover the original text.
+			rendered := RenderLine(style, "generated doc comment")
+			withComment := rendered + "\n" + src
+			_, stripped := Strip(withComment, style)
+			if len(stripped) != 1 {
+				t.Fatalf("expected exactly one comment after round-trip, got %d", len(stripped))
+			}
+			if stripped[0].Text != " generated doc comment" && stripped[0].Text != "generated doc comment" {
+				t.Fatalf("round-tripped comment text = %q", stripped[0].Text)
+			}
+		})
+	}
+}
+
+func TestDetectOverride(t *testing.T) {
+	style, ok := Detect("weird.xyz", DetectConfig{Overrides: map[string]string{".xyz": "Python"}})
+	if !ok {
+		t.Fatal("Detect with override failed")
+	}
+	if style.Name != "Python" {
+		t.Fatalf("Detect with override = %q, want Python", style.Name)
+	}
+}
+
+func TestDetectUnknownExtension(t *testing.T) {
+	if _, ok := Detect("mystery.zzz", DetectConfig{}); ok {
+		t.Fatal("Detect should fail for an unregistered extension")
+	}
+}
@@ -0,0 +1,168 @@
+// Package constval infers the folded value of Go constant declarations so
+// the commenter can append an auto-maintained "// <value>" hint next to
+// each constant, the same way gopls renders const values on hover.
+package constval
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"time"
+)
+
+// Annotation is the inferred value hint for a single constant name.
+type Annotation struct {
+	// Name is the constant identifier.
+	Name string
+	// Pos is the identifier's position, for callers that need to place
+	// the hint relative to the declaration.
+	Pos token.Pos
+	// Text is the rendered value, e.g. "16" or "16m40s".
+	Text string
+	// Ok reports whether Text holds a usable value. EvaluateFile sets it
+	// on every Annotation it returns: constants of kinds this package
+	// doesn't annotate, such as strings, and constants whose value
+	// couldn't be resolved get no Annotation at all.
+	Ok bool
+	// Suppressed reports whether the declaration's RHS is a bare int,
+	// float or bool literal whose value equals the folded value, e.g.
+	// "const dec = 500" folds to 500.
+	// Callers should skip emitting a hint when Suppressed is true.
+	Suppressed bool
+}
+
+// EvaluateFile parses and type-checks the Go source file src (named
+// filename for error messages and position info) and returns one
+// Annotation per named constant declared in it whose folded value it can
+// render, in declaration order.
+// Type-checking only ever sees this single file, so any identifier the
+// file relies on from a sibling file in the same package (the common
+// case for multi-file packages) resolves as "undefined" along with any
+// unresolvable import. Rather than aborting on that error, EvaluateFile
+// best-effort type-checks as much of the file as it can and omits the
+// individual constants that couldn't be resolved, leaving every other
+// constant in the file annotated normally.
+func EvaluateFile(filename string, src []byte) ([]Annotation, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("constval: parsing %s: %w", filename, err)
+	}
+
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	pkgName := file.Name.Name
+	// Ignore the error: conf.Check still populates info.Defs for every
+	// declaration it could resolve, and formatValue below reports
+	// ok=false for the ones it couldn't (their constant.Value is
+	// constant.Unknown), so evaluateSpec leaves them out.
+	conf.Check(pkgName, fset, []*ast.File{file}, info)
+
+	var out []Annotation
+	ast.Inspect(file, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			return true
+		}
+		for _, spec := range decl.Specs {
+			out = append(out, evaluateSpec(spec.(*ast.ValueSpec), info)...)
+		}
+		return true
+	})
+	return out, nil
+}
+
+func evaluateSpec(spec *ast.ValueSpec, info *types.Info) []Annotation {
+	var out []Annotation
+	for i, name := range spec.Names {
+		if name.Name == "_" {
+			continue
+		}
+		obj, ok := info.Defs[name].(*types.Const)
+		if !ok {
+			continue
+		}
+		text, ok := formatValue(obj)
+		if !ok {
+			continue
+		}
+		a := Annotation{Name: name.Name, Pos: name.Pos(), Text: text, Ok: true}
+		if i < len(spec.Values) {
+			a.Suppressed = literalMatches(spec.Values[i], obj.Val())
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// literalMatches reports whether expr is a bare int/float/bool literal
+// whose folded value equals val, meaning a generated hint would be
+// redundant.
+func literalMatches(expr ast.Expr, val constant.Value) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return false
+		}
+		litVal := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return litVal.Kind() != constant.Unknown && constant.Compare(litVal, token.EQL, val)
+	case *ast.Ident:
+		if e.Name != "true" && e.Name != "false" {
+			return false
+		}
+		return constant.Compare(constant.MakeBool(e.Name == "true"), token.EQL, val)
+	default:
+		return false
+	}
+}
+
+// formatValue renders a constant's folded value the way gopls's hover
+// does: bare decimal for untyped/typed numeric constants and booleans,
+// and the human-readable String() form for named durations. It reports
+// ok=false for kinds this package intentionally leaves alone, such as
+// strings, whose literal already is the value.
+func formatValue(c *types.Const) (text string, ok bool) {
+	val := c.Val()
+
+	if d, ok := durationValue(c.Type(), val); ok {
+		return d.String(), true
+	}
+
+	switch val.Kind() {
+	case constant.Bool:
+		return val.String(), true
+	case constant.Int:
+		return val.ExactString(), true
+	case constant.Float:
+		f, _ := constant.Float64Val(val)
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// durationValue reports the time.Duration value of val when typ is the
+// named type time.Duration itself; a type defined from it (e.g.
+// "type Timeout time.Duration") is not recognized.
+func durationValue(typ types.Type, val constant.Value) (time.Duration, bool) {
+	named, ok := typ.(*types.Named)
+	if !ok || val.Kind() != constant.Int {
+		return 0, false
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != "time" || obj.Name() != "Duration" {
+		return 0, false
+	}
+	n, exact := constant.Int64Val(val)
+	if !exact {
+		return 0, false
+	}
+	return time.Duration(n), true
+}
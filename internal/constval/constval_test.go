@@ -0,0 +1,161 @@
+package constval
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func annotationByName(anns []Annotation, name string) (Annotation, bool) {
+	for _, a := range anns {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Annotation{}, false
+}
+
+func TestEvaluateFileFixture(t *testing.T) {
+	path := filepath.Join("..", "..", "test", "go-without-docs.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	anns, err := EvaluateFile(path, src)
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		text       string
+		suppressed bool
+	}{
+		{"Red", "0", false},
+		{"Green", "1", false},
+		{"Blue", "2", false},
+		{"PI", "3.14159265359", true},
+	}
+	for _, c := range cases {
+		a, ok := annotationByName(anns, c.name)
+		if !ok {
+			t.Fatalf("no annotation for %s", c.name)
+		}
+		if a.Text != c.text {
+			t.Errorf("%s: Text = %q, want %q", c.name, a.Text, c.text)
+		}
+		if a.Suppressed != c.suppressed {
+			t.Errorf("%s: Suppressed = %v, want %v", c.name, a.Suppressed, c.suppressed)
+		}
+	}
+}
+
+func TestEvaluateFileSuppressesMatchingLiteral(t *testing.T) {
+	const src = `package sample
+
+const dec = 500
+`
+	anns, err := EvaluateFile("sample.go", []byte(src))
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+	a, ok := annotationByName(anns, "dec")
+	if !ok {
+		t.Fatal("no annotation for dec")
+	}
+	if a.Text != "500" || !a.Suppressed {
+		t.Errorf("dec: Text=%q Suppressed=%v, want Text=500 Suppressed=true", a.Text, a.Suppressed)
+	}
+}
+
+func TestEvaluateFileMultiNameAndBool(t *testing.T) {
+	const src = `package sample
+
+const a, b = 1, 2
+
+const enabled = true
+`
+	anns, err := EvaluateFile("sample.go", []byte(src))
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+	for _, c := range []struct{ name, text string }{{"a", "1"}, {"b", "2"}, {"enabled", "true"}} {
+		got, ok := annotationByName(anns, c.name)
+		if !ok {
+			t.Fatalf("no annotation for %s", c.name)
+		}
+		if got.Text != c.text {
+			t.Errorf("%s: Text = %q, want %q", c.name, got.Text, c.text)
+		}
+		if !got.Suppressed {
+			t.Errorf("%s: expected Suppressed since RHS literal already matches", c.name)
+		}
+	}
+}
+
+func TestEvaluateFileTypedDuration(t *testing.T) {
+	const src = `package sample
+
+import "time"
+
+const timeout = 1000 * time.Millisecond
+`
+	anns, err := EvaluateFile("sample.go", []byte(src))
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+	a, ok := annotationByName(anns, "timeout")
+	if !ok {
+		t.Fatal("no annotation for timeout")
+	}
+	if a.Text != "1s" {
+		t.Errorf("timeout: Text = %q, want %q", a.Text, "1s")
+	}
+	if a.Suppressed {
+		t.Errorf("timeout: expected Suppressed=false since RHS is not a bare literal")
+	}
+}
+
+func TestEvaluateFileSkipsStrings(t *testing.T) {
+	const src = `package sample
+
+const greeting = "hello"
+`
+	anns, err := EvaluateFile("sample.go", []byte(src))
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+	if _, ok := annotationByName(anns, "greeting"); ok {
+		t.Error("expected no annotation for a string constant")
+	}
+}
+
+// TestEvaluateFileToleratesUnresolvedSiblingFileSymbol guards against the
+// single-file type-check aborting the whole file the moment one constant
+// depends on an identifier defined elsewhere in the package (the common
+// case for multi-file packages, which EvaluateFile can't see since it
+// only ever type-checks the one file it's given).
+func TestEvaluateFileToleratesUnresolvedSiblingFileSymbol(t *testing.T) {
+	const src = `package sample
+
+const Count = helperValue
+
+const Other = 42
+`
+	anns, err := EvaluateFile("sample.go", []byte(src))
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+
+	if _, ok := annotationByName(anns, "Count"); ok {
+		t.Error("expected no annotation for Count, whose value can't be resolved from this file alone")
+	}
+	other, ok := annotationByName(anns, "Other")
+	if !ok {
+		t.Fatal("no annotation for Other")
+	}
+	if other.Text != "42" || !other.Suppressed {
+		t.Errorf("Other: Text=%q Suppressed=%v, want Text=42 Suppressed=true", other.Text, other.Suppressed)
+	}
+}
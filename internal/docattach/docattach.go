@@ -0,0 +1,159 @@
+// Package docattach places generated comment text onto the correct AST
+// node's Doc (or trailing Comment) slot and re-renders the file, instead
+// of splicing free-floating *ast.Comment nodes that a later gofmt pass
+// could reflow away from the declaration they document. It works at the
+// byte level: it locates a node's existing comment (if any) by position,
+// replaces or inserts the rendered comment text in the source, and hands
+// the result to go/format so the final positions are exactly what gofmt
+// itself would produce.
+package docattach
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// AttachFuncDoc sets doc as decl's doc comment. Per Go convention, doc
+// must start with decl's name (for a method, its bare method name, not
+// the receiver).
+func AttachFuncDoc(fset *token.FileSet, src []byte, decl *ast.FuncDecl, doc string) ([]byte, error) {
+	if err := requirePrefix(doc, decl.Name.Name); err != nil {
+		return nil, err
+	}
+	return AttachLeadingDoc(fset, src, decl.Pos(), decl.Doc, splitLines(doc))
+}
+
+// AttachFieldDoc sets doc as the doc comment of an interface method or
+// struct field. When field names one or more identifiers (as an
+// interface method does), doc must start with the first one.
+func AttachFieldDoc(fset *token.FileSet, src []byte, field *ast.Field, doc string) ([]byte, error) {
+	if len(field.Names) > 0 {
+		if err := requirePrefix(doc, field.Names[0].Name); err != nil {
+			return nil, err
+		}
+	}
+	return AttachLeadingDoc(fset, src, field.Pos(), field.Doc, splitLines(doc))
+}
+
+// AttachGenDeclDoc sets doc as the group-level doc comment of a type,
+// const, or var declaration (including a parenthesized group such as a
+// `const ( ... )` block).
+func AttachGenDeclDoc(fset *token.FileSet, src []byte, decl *ast.GenDecl, doc string) ([]byte, error) {
+	return AttachLeadingDoc(fset, src, decl.Pos(), decl.Doc, splitLines(doc))
+}
+
+// AttachValueComment sets text as spec's trailing, same-line comment
+// (e.g. an inferred constant value hint like "// 16"). It replaces the
+// whole comment, so for a multi-name spec such as `const a, b = 1, 2`
+// the caller is responsible for combining every name's hint into text.
+func AttachValueComment(fset *token.FileSet, src []byte, spec *ast.ValueSpec, text string) ([]byte, error) {
+	return AttachTrailingComment(fset, src, spec.End(), spec.Comment, text)
+}
+
+func requirePrefix(doc, name string) error {
+	if !strings.HasPrefix(doc, name) {
+		return fmt.Errorf("docattach: doc comment %q must start with %q", firstLine(doc), name)
+	}
+	if rest := doc[len(name):]; rest != "" {
+		if r := []rune(rest)[0]; unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return fmt.Errorf("docattach: doc comment %q must start with %q as its first word", firstLine(doc), name)
+		}
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func splitLines(s string) []string {
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+// AttachLeadingDoc replaces (or, if existingDoc is nil, inserts) the
+// leading comment immediately before the node starting at pos with
+// lines rendered as "// "-prefixed comment lines matching the node's own
+// indentation, then reformats the result with go/format so its final
+// positions are stable under repeated gofmt passes.
+func AttachLeadingDoc(fset *token.FileSet, src []byte, pos token.Pos, existingDoc *ast.CommentGroup, lines []string) ([]byte, error) {
+	nodeOffset := fset.Position(pos).Offset
+	indent := indentAt(src, nodeOffset)
+	rendered := renderLeadingComment(lines, indent)
+
+	start := nodeOffset - len(indent)
+	end := start
+	if existingDoc != nil {
+		docStart := fset.Position(existingDoc.Pos()).Offset
+		docIndent := indentAt(src, docStart)
+		start = docStart - len(docIndent)
+		end = fset.Position(existingDoc.End()).Offset
+		if end < len(src) && src[end] == '\r' {
+			end++
+		}
+		if end < len(src) && src[end] == '\n' {
+			end++ // swallow the newline the old comment owned
+		}
+	}
+	return formatSpliced(src, start, end, rendered)
+}
+
+// AttachTrailingComment replaces (or, if existing is nil, appends) the
+// same-line comment following the node ending at endPos with " // text".
+func AttachTrailingComment(fset *token.FileSet, src []byte, endPos token.Pos, existing *ast.CommentGroup, text string) ([]byte, error) {
+	start := fset.Position(endPos).Offset
+	end := start
+	rendered := []byte(" // " + text)
+	if existing != nil {
+		start = fset.Position(existing.Pos()).Offset
+		end = fset.Position(existing.End()).Offset
+		rendered = []byte("// " + text)
+	}
+	return formatSpliced(src, start, end, rendered)
+}
+
+func formatSpliced(src []byte, start, end int, rendered []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Write(src[:start])
+	out.Write(rendered)
+	out.Write(src[end:])
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("docattach: reformatting after splice: %w", err)
+	}
+	return formatted, nil
+}
+
+// renderLeadingComment renders lines as "// "-prefixed comment lines,
+// each indented with indent and terminated with a newline.
+func renderLeadingComment(lines []string, indent string) []byte {
+	var buf bytes.Buffer
+	for _, l := range lines {
+		buf.WriteString(indent)
+		if l == "" {
+			buf.WriteString("//")
+		} else {
+			buf.WriteString("// ")
+			buf.WriteString(l)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// indentAt returns the leading whitespace of the line containing offset.
+func indentAt(src []byte, offset int) string {
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+	i := lineStart
+	for i < offset && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	return string(src[lineStart:i])
+}
@@ -0,0 +1,267 @@
+package docattach
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelikhan/action-continuous-comments/internal/constval"
+)
+
+// reparse re-parses src (with comments) into a fresh AST, since every
+// Attach* call reformats the file and shifts all positions: callers must
+// re-locate nodes in the new tree before making another edit.
+func reparse(t *testing.T, src []byte) (*token.FileSet, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("reparse: %v\n--- source ---\n%s", err, src)
+	}
+	return fset, file
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, d := range file.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+func findGenDecl(file *ast.File, specName string) *ast.GenDecl {
+	for _, d := range file.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.Name == specName {
+					return gd
+				}
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if n.Name == specName {
+						return gd
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func findValueSpec(gd *ast.GenDecl, name string) *ast.ValueSpec {
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, n := range vs.Names {
+			if n.Name == name {
+				return vs
+			}
+		}
+	}
+	return nil
+}
+
+func findInterfaceMethod(file *ast.File, iface, method string) *ast.Field {
+	gd := findGenDecl(file, iface)
+	if gd == nil {
+		return nil
+	}
+	ts, ok := gd.Specs[0].(*ast.TypeSpec)
+	if !ok {
+		return nil
+	}
+	it, ok := ts.Type.(*ast.InterfaceType)
+	if !ok {
+		return nil
+	}
+	for _, f := range it.Methods.List {
+		for _, n := range f.Names {
+			if n.Name == method {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func TestAttachFuncDocOnPlainFunction(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+	fn := findFunc(file, "AddNumbers")
+	if fn == nil {
+		t.Fatal("AddNumbers not found")
+	}
+
+	out, err := AttachFuncDoc(fset, src, fn, "AddNumbers returns the sum of a and b.")
+	if err != nil {
+		t.Fatalf("AttachFuncDoc: %v", err)
+	}
+
+	_, file2 := reparse(t, out)
+	fn2 := findFunc(file2, "AddNumbers")
+	if fn2.Doc == nil {
+		t.Fatal("AddNumbers has no Doc after attach")
+	}
+	if got := strings.TrimSpace(fn2.Doc.Text()); got != "AddNumbers returns the sum of a and b." {
+		t.Errorf("Doc.Text() = %q", got)
+	}
+}
+
+func TestAttachFuncDocOnReceiverMethod(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+	fn := findFunc(file, "Move")
+	if fn == nil {
+		t.Fatal("Move not found")
+	}
+	if fn.Recv == nil {
+		t.Fatal("Move should have a pointer receiver")
+	}
+
+	out, err := AttachFuncDoc(fset, src, fn, "Move translates p by (dx, dy).")
+	if err != nil {
+		t.Fatalf("AttachFuncDoc: %v", err)
+	}
+
+	_, file2 := reparse(t, out)
+	fn2 := findFunc(file2, "Move")
+	if fn2.Doc == nil || !strings.HasPrefix(fn2.Doc.Text(), "Move ") {
+		t.Fatalf("Move.Doc = %q, want it to start with the method name", fn2.Doc.Text())
+	}
+}
+
+func TestAttachFuncDocRejectsWrongPrefix(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+	fn := findFunc(file, "AddNumbers")
+	if _, err := AttachFuncDoc(fset, src, fn, "Adds two numbers."); err == nil {
+		t.Fatal("expected an error when the doc doesn't start with the function name")
+	}
+}
+
+func TestAttachFuncDocRejectsPrefixWithoutWordBoundary(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+	fn := findFunc(file, "AddNumbers")
+	if _, err := AttachFuncDoc(fset, src, fn, "AddNumbersAndMore does extra work."); err == nil {
+		t.Fatal("expected an error: the first word is AddNumbersAndMore, not AddNumbers")
+	}
+}
+
+func TestAttachFieldDocOnInterfaceMethod(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+	field := findInterfaceMethod(file, "Drawable", "Draw")
+	if field == nil {
+		t.Fatal("Drawable.Draw not found")
+	}
+
+	out, err := AttachFieldDoc(fset, src, field, "Draw renders the shape to the current context.")
+	if err != nil {
+		t.Fatalf("AttachFieldDoc: %v", err)
+	}
+
+	_, file2 := reparse(t, out)
+	field2 := findInterfaceMethod(file2, "Drawable", "Draw")
+	if field2.Doc == nil {
+		t.Fatal("Draw has no Doc after attach")
+	}
+	if got := strings.TrimSpace(field2.Doc.Text()); got != "Draw renders the shape to the current context." {
+		t.Errorf("Doc.Text() = %q", got)
+	}
+}
+
+func TestAttachConstGroupDocAndPerSpecValues(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+
+	gd := findGenDecl(file, "Red")
+	if gd == nil {
+		t.Fatal("Color const group not found")
+	}
+	out, err := AttachGenDeclDoc(fset, src, gd, "Color enumerates the supported colors.")
+	if err != nil {
+		t.Fatalf("AttachGenDeclDoc: %v", err)
+	}
+
+	anns, err := constval.EvaluateFile("sample.go", out)
+	if err != nil {
+		t.Fatalf("EvaluateFile: %v", err)
+	}
+	values := map[string]string{}
+	for _, a := range anns {
+		if a.Ok {
+			values[a.Name] = a.Text
+		}
+	}
+
+	for _, name := range []string{"Red", "Green", "Blue"} {
+		fset, file := reparse(t, out)
+		gd := findGenDecl(file, "Red")
+		spec := findValueSpec(gd, name)
+		if spec == nil {
+			t.Fatalf("%s spec not found", name)
+		}
+		out, err = AttachValueComment(fset, out, spec, values[name])
+		if err != nil {
+			t.Fatalf("AttachValueComment(%s): %v", name, err)
+		}
+	}
+
+	fset, file = reparse(t, out)
+	gd = findGenDecl(file, "Red")
+	if gd.Doc == nil || strings.TrimSpace(gd.Doc.Text()) != "Color enumerates the supported colors." {
+		t.Fatalf("group Doc = %q", gd.Doc.Text())
+	}
+	for name, want := range map[string]string{"Red": "0", "Green": "1", "Blue": "2"} {
+		spec := findValueSpec(gd, name)
+		if spec.Comment == nil {
+			t.Fatalf("%s has no trailing value comment", name)
+		}
+		if got := strings.TrimSpace(spec.Comment.Text()); got != want {
+			t.Errorf("%s value comment = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestAttachIsIdempotentUnderGofmt(t *testing.T) {
+	src := readFixture(t)
+	fset, file := reparse(t, src)
+	fn := findFunc(file, "AddNumbers")
+
+	out, err := AttachFuncDoc(fset, src, fn, "AddNumbers returns the sum of a and b.")
+	if err != nil {
+		t.Fatalf("AttachFuncDoc: %v", err)
+	}
+
+	again, err := format.Source(out)
+	if err != nil {
+		t.Fatalf("format.Source: %v", err)
+	}
+	if string(again) != string(out) {
+		t.Fatalf("output is not stable under a second gofmt pass:\n--- first ---\n%s\n--- second ---\n%s", out, again)
+	}
+}
+
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+	path := filepath.Join("..", "..", "test", "go-without-docs.go")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return data
+}